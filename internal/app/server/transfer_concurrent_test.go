@@ -0,0 +1,78 @@
+// Package server holds integration-level checks for the application's
+// store-driven behavior that are easier to express against a real database
+// than as unit tests.
+package server
+
+import (
+	"context"
+	"ewallet/internal/app/store"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	concurrentTransferWalletBalance = 1_000_000_00
+	concurrentTransferAmount        = 100
+	concurrentTransferCount         = 1000
+)
+
+// TestConcurrentTransfersConserveBalance fires concurrentTransferCount
+// concurrent transfers back and forth between two wallets and asserts the
+// sum of their balances is conserved, guarding TransferMoney's lock
+// ordering against lost updates under contention.
+//
+// This is a regular test rather than a testing.B benchmark: the workload
+// size is fixed at concurrentTransferCount, not scaled by b.N.
+func TestConcurrentTransfersConserveBalance(t *testing.T) {
+	databaseURL := os.Getenv("EWALLET_TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("EWALLET_TEST_DATABASE_URL not set")
+	}
+
+	st := store.New(&store.Config{DatabaseURL: databaseURL})
+	if err := st.Open(); err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	ctx := context.Background()
+	walletA, err := st.GetWalletDB().Create(ctx, uuid.New().String(), "bench-owner", "A", "USD", concurrentTransferWalletBalance, nil)
+	if err != nil {
+		t.Fatalf("failed to create wallet A: %v", err)
+	}
+	walletB, err := st.GetWalletDB().Create(ctx, uuid.New().String(), "bench-owner", "B", "USD", concurrentTransferWalletBalance, nil)
+	if err != nil {
+		t.Fatalf("failed to create wallet B: %v", err)
+	}
+
+	initialTotal := walletA.Balance + walletB.Balance
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentTransferCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			from, to := walletA.ID, walletB.ID
+			if i%2 == 1 {
+				from, to = walletB.ID, walletA.ID
+			}
+			_ = st.GetTransactionDB().TransferMoney(ctx, from, to, concurrentTransferAmount)
+		}(i)
+	}
+	wg.Wait()
+
+	finalA, err := st.GetWalletDB().CheckStatus(ctx, walletA.ID)
+	if err != nil {
+		t.Fatalf("failed to check wallet A: %v", err)
+	}
+	finalB, err := st.GetWalletDB().CheckStatus(ctx, walletB.ID)
+	if err != nil {
+		t.Fatalf("failed to check wallet B: %v", err)
+	}
+
+	if finalA.Balance+finalB.Balance != initialTotal {
+		t.Fatalf("balance not conserved: got %d, want %d", finalA.Balance+finalB.Balance, initialTotal)
+	}
+}
@@ -0,0 +1,6 @@
+package store
+
+// Config holds the parameters needed to connect to the underlying database.
+type Config struct {
+	DatabaseURL string
+}
@@ -0,0 +1,13 @@
+package store
+
+import "errors"
+
+// Sentinel errors returned by the store layer so callers can classify
+// failures with errors.Is instead of matching on error strings.
+var (
+	ErrWalletNotFound                  = errors.New("wallet not found")
+	ErrTargetWalletNotFound            = errors.New("target wallet not found")
+	ErrInsufficientFunds               = errors.New("there are not enough funds")
+	ErrTransferInitiationNotRetryable  = errors.New("transfer initiation is not in a retryable state")
+	ErrTransferInitiationNotReversible = errors.New("transfer initiation is not in a reversible state")
+)
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXProvider supplies the exchange rate to use when converting an amount
+// from one ISO 4217 currency to another at transfer time.
+type FXProvider interface {
+	Rate(ctx context.Context, from, to string) (rate decimal.Decimal, asOf time.Time, err error)
+}
+
+// InMemoryFXProvider is the default FXProvider: a static table of rates,
+// suitable for tests and local development. Converting a currency to itself
+// always returns a rate of 1 regardless of what the table holds.
+type InMemoryFXProvider struct {
+	Rates map[string]map[string]decimal.Decimal
+}
+
+// NewInMemoryFXProvider returns an InMemoryFXProvider with an empty rate
+// table; populate Rates before use.
+func NewInMemoryFXProvider() *InMemoryFXProvider {
+	return &InMemoryFXProvider{Rates: map[string]map[string]decimal.Decimal{}}
+}
+
+// Rate implements FXProvider.
+func (p *InMemoryFXProvider) Rate(_ context.Context, from, to string) (decimal.Decimal, time.Time, error) {
+	if from == to {
+		return decimal.NewFromInt(1), time.Now(), nil
+	}
+
+	if rate, ok := p.Rates[from][to]; ok {
+		return rate, time.Now(), nil
+	}
+
+	return decimal.Decimal{}, time.Time{}, fmt.Errorf("store: no FX rate from %s to %s", from, to)
+}
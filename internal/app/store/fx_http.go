@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HTTPFXProvider is a stub FXProvider that fetches a rate from an external
+// rates service at GET {BaseURL}/rates?from=...&to=..., expecting a JSON
+// body of the form {"rate": "1.0823", "as_of": "2024-01-01T00:00:00Z"}.
+type HTTPFXProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewHTTPFXProvider returns an HTTPFXProvider backed by http.DefaultClient.
+func NewHTTPFXProvider(baseURL string) *HTTPFXProvider {
+	return &HTTPFXProvider{BaseURL: baseURL, Client: http.DefaultClient}
+}
+
+// Rate implements FXProvider.
+func (p *HTTPFXProvider) Rate(ctx context.Context, from, to string) (decimal.Decimal, time.Time, error) {
+	query := url.Values{"from": {from}, "to": {to}}
+	endpoint := strings.TrimSuffix(p.BaseURL, "/") + "/rates?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decimal.Decimal{}, time.Time{}, fmt.Errorf("store: fx provider returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Rate string    `json:"rate"`
+		AsOf time.Time `json:"as_of"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return decimal.Decimal{}, time.Time{}, err
+	}
+
+	rate, err := decimal.NewFromString(body.Rate)
+	if err != nil {
+		return decimal.Decimal{}, time.Time{}, err
+	}
+
+	return rate, body.AsOf, nil
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HistoryCursor identifies a position in the wallet_id, created_at DESC, id
+// DESC ordering used by ListHistory, so pagination stays stable under
+// concurrent inserts.
+type HistoryCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// EncodeHistoryCursor returns the opaque, base64-encoded form of c.
+func EncodeHistoryCursor(c HistoryCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeHistoryCursor parses a cursor produced by EncodeHistoryCursor.
+func DecodeHistoryCursor(s string) (HistoryCursor, error) {
+	raw, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return HistoryCursor{}, fmt.Errorf("store: malformed cursor: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return HistoryCursor{}, errors.New("store: malformed cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return HistoryCursor{}, fmt.Errorf("store: malformed cursor: %w", err)
+	}
+
+	return HistoryCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// HistoryDirection filters ListHistory to transactions moving money out of
+// the wallet, into it, or either.
+type HistoryDirection string
+
+const (
+	HistoryDirectionIn  HistoryDirection = "in"
+	HistoryDirectionOut HistoryDirection = "out"
+	HistoryDirectionAll HistoryDirection = "all"
+)
+
+// HistoryFilter narrows a ListHistory query. The zero value matches every
+// transaction for the wallet.
+type HistoryFilter struct {
+	Cursor    *HistoryCursor
+	Limit     int
+	Direction HistoryDirection
+	Since     *time.Time
+	Until     *time.Time
+	MinAmount *int64
+	MaxAmount *int64
+}
+
+// amountColumnFor returns the SQL expression holding the amount walletID
+// itself moved for a transaction, given filter's direction: source_amount
+// when walletID is known to be the sender, credited_amount when it's known
+// to be the recipient, and a per-row CASE when either is possible.
+func amountColumnFor(direction HistoryDirection, walletID string, arg func(interface{}) string) string {
+	switch direction {
+	case HistoryDirectionIn:
+		return "credited_amount"
+	case HistoryDirectionOut:
+		return "source_amount"
+	default:
+		return fmt.Sprintf("(CASE WHEN from_wallet_id = %s THEN source_amount ELSE credited_amount END)", arg(walletID))
+	}
+}
+
+// ListHistory returns up to filter.Limit+1 transactions for walletID,
+// newest first, matching filter; a filter.Limit of zero or less is treated
+// as 50, so up to 51 rows are returned. Callers use the extra row to determine
+// has_more without a separate count query. It returns sql.ErrNoRows if
+// walletID does not belong to a known wallet.
+func (t *TransactionDB) ListHistory(ctx context.Context, walletID string, filter HistoryFilter) ([]Transaction, error) {
+	exists, err := t.walletExists(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := strings.Builder{}
+	query.WriteString(`SELECT id, from_wallet_id, to_wallet_id, source_amount, source_currency,
+	                           credited_amount, credited_currency, rate, created_at
+	                    FROM transactions WHERE `)
+
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	switch filter.Direction {
+	case HistoryDirectionOut:
+		query.WriteString(fmt.Sprintf("from_wallet_id = %s", arg(walletID)))
+	case HistoryDirectionIn:
+		query.WriteString(fmt.Sprintf("to_wallet_id = %s", arg(walletID)))
+	default:
+		ref := arg(walletID)
+		query.WriteString(fmt.Sprintf("(from_wallet_id = %s OR to_wallet_id = %s)", ref, ref))
+	}
+
+	if filter.Cursor != nil {
+		createdAtArg := arg(filter.Cursor.CreatedAt)
+		idArg := arg(filter.Cursor.ID)
+		query.WriteString(fmt.Sprintf(" AND (created_at < %s OR (created_at = %s AND id < %s))", createdAtArg, createdAtArg, idArg))
+	}
+	if filter.Since != nil {
+		query.WriteString(fmt.Sprintf(" AND created_at >= %s", arg(*filter.Since)))
+	}
+	if filter.Until != nil {
+		query.WriteString(fmt.Sprintf(" AND created_at <= %s", arg(*filter.Until)))
+	}
+	if filter.MinAmount != nil || filter.MaxAmount != nil {
+		// The amount the wallet actually moved is source_amount when it's the
+		// sender and credited_amount when it's the recipient, since those can
+		// differ across a currency conversion. HistoryDirectionAll has to
+		// pick per-row, since either side could be walletID.
+		amountColumn := amountColumnFor(filter.Direction, walletID, arg)
+
+		if filter.MinAmount != nil {
+			query.WriteString(fmt.Sprintf(" AND %s >= %s", amountColumn, arg(*filter.MinAmount)))
+		}
+		if filter.MaxAmount != nil {
+			query.WriteString(fmt.Sprintf(" AND %s <= %s", amountColumn, arg(*filter.MaxAmount)))
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query.WriteString(fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT %s", arg(limit+1)))
+
+	rows, err := t.store.db.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []Transaction
+	for rows.Next() {
+		var tx Transaction
+		if err := rows.Scan(&tx.ID, &tx.FromWalletID, &tx.ToWalletID, &tx.SourceAmount, &tx.SourceCurrency,
+			&tx.CreditedAmount, &tx.CreditedCurrency, &tx.Rate, &tx.CreatedAt); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, rows.Err()
+}
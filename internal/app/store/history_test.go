@@ -0,0 +1,36 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	want := HistoryCursor{
+		CreatedAt: time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		ID:        "tx-123",
+	}
+
+	got, err := DecodeHistoryCursor(EncodeHistoryCursor(want))
+	if err != nil {
+		t.Fatalf("failed to decode cursor: %v", err)
+	}
+
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("cursor round-trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeHistoryCursorRejectsMalformedInput(t *testing.T) {
+	cases := []string{
+		"",
+		"not-base64!!!",
+		EncodeHistoryCursor(HistoryCursor{}) + "x",
+	}
+
+	for _, c := range cases {
+		if _, err := DecodeHistoryCursor(c); err == nil {
+			t.Errorf("expected DecodeHistoryCursor(%q) to fail", c)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+// Store is the top-level handle to the database and its sub-stores.
+type Store struct {
+	config               *Config
+	db                   *sql.DB
+	WalletDB             *WalletDB
+	transactionDB        *TransactionDB
+	transferInitiationDB *TransferInitiationDB
+	walletAddressDB      *WalletAddressDB
+
+	// FXProvider supplies exchange rates for cross-currency transfers. It
+	// defaults to an empty InMemoryFXProvider; set it after New to override.
+	FXProvider FXProvider
+}
+
+// New creates a Store that is not yet connected to the database.
+func New(config *Config) *Store {
+	return &Store{
+		config:     config,
+		FXProvider: NewInMemoryFXProvider(),
+	}
+}
+
+// Open connects to the database and wires up the sub-stores.
+func (s *Store) Open() error {
+	db, err := sql.Open("postgres", s.config.DatabaseURL)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	s.db = db
+	s.WalletDB = &WalletDB{store: s}
+	s.transactionDB = &TransactionDB{store: s}
+	s.transferInitiationDB = &TransferInitiationDB{store: s}
+	s.walletAddressDB = &WalletAddressDB{store: s}
+
+	return nil
+}
+
+// GetWalletDB returns the wallet sub-store.
+func (s *Store) GetWalletDB() *WalletDB {
+	return s.WalletDB
+}
+
+// GetTransactionDB returns the transaction sub-store.
+func (s *Store) GetTransactionDB() *TransactionDB {
+	return s.transactionDB
+}
+
+// GetTransferInitiationDB returns the transfer initiation sub-store.
+func (s *Store) GetTransferInitiationDB() *TransferInitiationDB {
+	return s.transferInitiationDB
+}
+
+// GetWalletAddressDB returns the wallet address sub-store.
+func (s *Store) GetWalletAddressDB() *WalletAddressDB {
+	return s.walletAddressDB
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transaction represents a single completed transfer between two wallets.
+// SourceAmount/SourceCurrency is what left the "from" wallet; CreditedAmount
+// /CreditedCurrency is what the "to" wallet received, converted at Rate.
+// Amounts are in minor units of their respective currency.
+type Transaction struct {
+	ID               string          `json:"id"`
+	FromWalletID     string          `json:"from_wallet_id"`
+	ToWalletID       string          `json:"to_wallet_id"`
+	SourceAmount     int64           `json:"source_amount"`
+	SourceCurrency   string          `json:"source_currency"`
+	CreditedAmount   int64           `json:"credited_amount"`
+	CreditedCurrency string          `json:"credited_currency"`
+	Rate             decimal.Decimal `json:"rate"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// TransactionDB manages transaction rows and the transfer of funds between
+// wallets.
+type TransactionDB struct {
+	store *Store
+}
+
+// TransferMoney debits the "from" wallet by amount (in its own currency's
+// minor units), converts it to the "to" wallet's currency via the store's
+// FXProvider, and credits the result. The whole operation runs in a single
+// transaction that locks both wallet rows, always acquiring them in id
+// order, so concurrent transfers between the same pair of wallets can never
+// deadlock. It returns sql.ErrNoRows if the source wallet does not exist,
+// ErrTargetWalletNotFound if the destination wallet does not exist, and
+// ErrInsufficientFunds if the source wallet's balance is lower than amount.
+func (t *TransactionDB) TransferMoney(ctx context.Context, from, to string, amount int64) error {
+	tx, err := t.store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	first, second := from, to
+	if second < first {
+		first, second = second, first
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT id FROM wallets WHERE id = $1 FOR UPDATE", first); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "SELECT id FROM wallets WHERE id = $1 FOR UPDATE", second); err != nil {
+		return err
+	}
+
+	var sourceBalance int64
+	var sourceCurrency string
+	if err := tx.QueryRowContext(ctx,
+		"SELECT balance, currency FROM wallets WHERE id = $1", from,
+	).Scan(&sourceBalance, &sourceCurrency); err != nil {
+		return err
+	}
+
+	if sourceBalance < amount {
+		return ErrInsufficientFunds
+	}
+
+	var destCurrency string
+	if err := tx.QueryRowContext(ctx,
+		"SELECT currency FROM wallets WHERE id = $1", to,
+	).Scan(&destCurrency); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTargetWalletNotFound
+		}
+		return err
+	}
+
+	rate, _, err := t.store.FXProvider.Rate(ctx, sourceCurrency, destCurrency)
+	if err != nil {
+		return err
+	}
+
+	credited := decimal.NewFromInt(amount).Mul(rate).Round(0).IntPart()
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE wallets SET balance = balance - $1 WHERE id = $2", amount, from,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE wallets SET balance = balance + $1 WHERE id = $2", credited, to,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO transactions
+		 (from_wallet_id, to_wallet_id, source_amount, source_currency, credited_amount, credited_currency, rate)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		from, to, amount, sourceCurrency, credited, destCurrency, rate,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// walletExists reports whether id belongs to a known wallet.
+func (t *TransactionDB) walletExists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := t.store.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM wallets WHERE id = $1)", id,
+	).Scan(&exists)
+	return exists, err
+}
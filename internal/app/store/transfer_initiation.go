@@ -0,0 +1,347 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferInitiationStatus is the lifecycle state of a TransferInitiation.
+type TransferInitiationStatus string
+
+const (
+	TransferInitiationWaiting    TransferInitiationStatus = "WAITING"
+	TransferInitiationProcessing TransferInitiationStatus = "PROCESSING"
+	TransferInitiationProcessed  TransferInitiationStatus = "PROCESSED"
+	TransferInitiationFailed     TransferInitiationStatus = "FAILED"
+	TransferInitiationReversed   TransferInitiationStatus = "REVERSED"
+)
+
+// TransferInitiation is a persisted, idempotent request to move money
+// between two wallets. Unlike a Transaction, it carries a lifecycle and can
+// be retried or reversed.
+type TransferInitiation struct {
+	ID                  string
+	Reference           string
+	Amount              int64
+	SourceWalletID      string
+	DestinationWalletID string
+	Description         string
+	ScheduledAt         time.Time
+	Status              TransferInitiationStatus
+	RelatedTransferID   sql.NullString
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Adjustment records a single attempt to move a TransferInitiation forward.
+type Adjustment struct {
+	ID                   string
+	TransferInitiationID string
+	Status               TransferInitiationStatus
+	Error                string
+	CreatedAt            time.Time
+}
+
+// TransferInitiationDB manages transfer initiations and their adjustments.
+type TransferInitiationDB struct {
+	store *Store
+}
+
+// Create inserts a new transfer initiation, or returns the existing one if
+// reference has already been used (idempotency). reference is expected to
+// carry a unique constraint at the database level: the initial
+// GetByReference lookup is just a fast path, and two concurrent Creates
+// racing on the same reference are resolved by ON CONFLICT DO NOTHING below
+// rather than by the lookup, which can't see uncommitted concurrent inserts.
+func (d *TransferInitiationDB) Create(ctx context.Context, reference string, amount int64, sourceWalletID, destinationWalletID, description string, scheduledAt time.Time) (*TransferInitiation, error) {
+	if existing, err := d.GetByReference(ctx, reference); err == nil {
+		return existing, nil
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	ti := &TransferInitiation{
+		ID:                  uuid.New().String(),
+		Reference:           reference,
+		Amount:              amount,
+		SourceWalletID:      sourceWalletID,
+		DestinationWalletID: destinationWalletID,
+		Description:         description,
+		ScheduledAt:         scheduledAt,
+		Status:              TransferInitiationWaiting,
+	}
+
+	result, err := d.store.db.ExecContext(ctx,
+		`INSERT INTO transfer_initiations
+		 (id, reference, amount, source_wallet_id, destination_wallet_id, description, scheduled_at, status)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (reference) DO NOTHING`,
+		ti.ID, ti.Reference, ti.Amount, ti.SourceWalletID, ti.DestinationWalletID, ti.Description, ti.ScheduledAt, ti.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if inserted == 0 {
+		// Lost the race: a concurrent Create for the same reference
+		// committed first and the unique constraint silently dropped this
+		// insert. Return whichever transfer initiation actually won.
+		return d.GetByReference(ctx, reference)
+	}
+
+	if err := d.AddAdjustment(ctx, ti.ID, TransferInitiationWaiting, ""); err != nil {
+		return nil, err
+	}
+
+	return ti, nil
+}
+
+// Get returns the transfer initiation with the given id.
+func (d *TransferInitiationDB) Get(ctx context.Context, id string) (*TransferInitiation, error) {
+	ti := &TransferInitiation{}
+	err := d.store.db.QueryRowContext(ctx,
+		`SELECT id, reference, amount, source_wallet_id, destination_wallet_id, description,
+		        scheduled_at, status, related_transfer_id, created_at, updated_at
+		 FROM transfer_initiations WHERE id = $1`, id,
+	).Scan(&ti.ID, &ti.Reference, &ti.Amount, &ti.SourceWalletID, &ti.DestinationWalletID, &ti.Description,
+		&ti.ScheduledAt, &ti.Status, &ti.RelatedTransferID, &ti.CreatedAt, &ti.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return ti, nil
+}
+
+// GetByReference returns the transfer initiation created for reference, if any.
+func (d *TransferInitiationDB) GetByReference(ctx context.Context, reference string) (*TransferInitiation, error) {
+	ti := &TransferInitiation{}
+	err := d.store.db.QueryRowContext(ctx,
+		`SELECT id, reference, amount, source_wallet_id, destination_wallet_id, description,
+		        scheduled_at, status, related_transfer_id, created_at, updated_at
+		 FROM transfer_initiations WHERE reference = $1`, reference,
+	).Scan(&ti.ID, &ti.Reference, &ti.Amount, &ti.SourceWalletID, &ti.DestinationWalletID, &ti.Description,
+		&ti.ScheduledAt, &ti.Status, &ti.RelatedTransferID, &ti.CreatedAt, &ti.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return ti, nil
+}
+
+// List returns transfer initiations ordered by creation time, newest first.
+func (d *TransferInitiationDB) List(ctx context.Context, limit, offset int) ([]TransferInitiation, error) {
+	rows, err := d.store.db.QueryContext(ctx,
+		`SELECT id, reference, amount, source_wallet_id, destination_wallet_id, description,
+		        scheduled_at, status, related_transfer_id, created_at, updated_at
+		 FROM transfer_initiations ORDER BY created_at DESC LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var initiations []TransferInitiation
+	for rows.Next() {
+		var ti TransferInitiation
+		if err := rows.Scan(&ti.ID, &ti.Reference, &ti.Amount, &ti.SourceWalletID, &ti.DestinationWalletID, &ti.Description,
+			&ti.ScheduledAt, &ti.Status, &ti.RelatedTransferID, &ti.CreatedAt, &ti.UpdatedAt); err != nil {
+			return nil, err
+		}
+		initiations = append(initiations, ti)
+	}
+
+	return initiations, rows.Err()
+}
+
+// ListForWallet returns transfer initiations sourced from or destined to
+// walletID, ordered by creation time, newest first.
+func (d *TransferInitiationDB) ListForWallet(ctx context.Context, walletID string, limit, offset int) ([]TransferInitiation, error) {
+	rows, err := d.store.db.QueryContext(ctx,
+		`SELECT id, reference, amount, source_wallet_id, destination_wallet_id, description,
+		        scheduled_at, status, related_transfer_id, created_at, updated_at
+		 FROM transfer_initiations WHERE source_wallet_id = $1 OR destination_wallet_id = $1
+		 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+		walletID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var initiations []TransferInitiation
+	for rows.Next() {
+		var ti TransferInitiation
+		if err := rows.Scan(&ti.ID, &ti.Reference, &ti.Amount, &ti.SourceWalletID, &ti.DestinationWalletID, &ti.Description,
+			&ti.ScheduledAt, &ti.Status, &ti.RelatedTransferID, &ti.CreatedAt, &ti.UpdatedAt); err != nil {
+			return nil, err
+		}
+		initiations = append(initiations, ti)
+	}
+
+	return initiations, rows.Err()
+}
+
+// DueForProcessing returns every WAITING transfer initiation whose
+// scheduled_at has passed, for the background worker to pick up.
+func (d *TransferInitiationDB) DueForProcessing(ctx context.Context) ([]TransferInitiation, error) {
+	rows, err := d.store.db.QueryContext(ctx,
+		`SELECT id, reference, amount, source_wallet_id, destination_wallet_id, description,
+		        scheduled_at, status, related_transfer_id, created_at, updated_at
+		 FROM transfer_initiations WHERE status = $1 AND scheduled_at <= now()`,
+		TransferInitiationWaiting,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var initiations []TransferInitiation
+	for rows.Next() {
+		var ti TransferInitiation
+		if err := rows.Scan(&ti.ID, &ti.Reference, &ti.Amount, &ti.SourceWalletID, &ti.DestinationWalletID, &ti.Description,
+			&ti.ScheduledAt, &ti.Status, &ti.RelatedTransferID, &ti.CreatedAt, &ti.UpdatedAt); err != nil {
+			return nil, err
+		}
+		initiations = append(initiations, ti)
+	}
+
+	return initiations, rows.Err()
+}
+
+// UpdateStatus moves a transfer initiation to a new status.
+func (d *TransferInitiationDB) UpdateStatus(ctx context.Context, id string, status TransferInitiationStatus) error {
+	_, err := d.store.db.ExecContext(ctx,
+		"UPDATE transfer_initiations SET status = $1, updated_at = now() WHERE id = $2",
+		status, id,
+	)
+	return err
+}
+
+// LinkRelatedTransfer records the counterpart transfer initiation created by
+// a reversal.
+func (d *TransferInitiationDB) LinkRelatedTransfer(ctx context.Context, id, relatedID string) error {
+	_, err := d.store.db.ExecContext(ctx,
+		"UPDATE transfer_initiations SET related_transfer_id = $1, updated_at = now() WHERE id = $2",
+		relatedID, id,
+	)
+	return err
+}
+
+// Retry resets a FAILED transfer initiation back to WAITING so the worker
+// picks it up again. It returns ErrTransferInitiationNotRetryable if the
+// initiation is not currently FAILED.
+func (d *TransferInitiationDB) Retry(ctx context.Context, id string) (*TransferInitiation, error) {
+	ti, err := d.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ti.Status != TransferInitiationFailed {
+		return nil, ErrTransferInitiationNotRetryable
+	}
+
+	if err := d.UpdateStatus(ctx, id, TransferInitiationWaiting); err != nil {
+		return nil, err
+	}
+	if err := d.AddAdjustment(ctx, id, TransferInitiationWaiting, ""); err != nil {
+		return nil, err
+	}
+
+	ti.Status = TransferInitiationWaiting
+	return ti, nil
+}
+
+// Reverse creates a new transfer initiation mirroring ti from destination
+// back to source, links the two via related_transfer_id, and marks ti as
+// REVERSED.
+func (d *TransferInitiationDB) Reverse(ctx context.Context, id string) (*TransferInitiation, error) {
+	ti, err := d.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ti.Status != TransferInitiationProcessed {
+		return nil, ErrTransferInitiationNotReversible
+	}
+
+	mirror, err := d.Create(ctx,
+		"reversal-of-"+ti.ID,
+		ti.Amount,
+		ti.DestinationWalletID,
+		ti.SourceWalletID,
+		"reversal of "+ti.Reference,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.LinkRelatedTransfer(ctx, mirror.ID, ti.ID); err != nil {
+		return nil, err
+	}
+	if err := d.LinkRelatedTransfer(ctx, ti.ID, mirror.ID); err != nil {
+		return nil, err
+	}
+	if err := d.UpdateStatus(ctx, ti.ID, TransferInitiationReversed); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// PendingOutboundTotal sums the amount of every WAITING or PROCESSING
+// transfer initiation sourced from walletID, for computing available
+// balance.
+func (d *TransferInitiationDB) PendingOutboundTotal(ctx context.Context, walletID string) (int64, error) {
+	var total sql.NullInt64
+	err := d.store.db.QueryRowContext(ctx,
+		`SELECT SUM(amount) FROM transfer_initiations
+		 WHERE source_wallet_id = $1 AND status IN ($2, $3)`,
+		walletID, TransferInitiationWaiting, TransferInitiationProcessing,
+	).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total.Int64, nil
+}
+
+// AddAdjustment records a single attempt against a transfer initiation.
+func (d *TransferInitiationDB) AddAdjustment(ctx context.Context, transferInitiationID string, status TransferInitiationStatus, errMsg string) error {
+	_, err := d.store.db.ExecContext(ctx,
+		"INSERT INTO adjustments (id, transfer_initiation_id, status, error) VALUES ($1, $2, $3, $4)",
+		uuid.New().String(), transferInitiationID, status, errMsg,
+	)
+	return err
+}
+
+// Adjustments returns every adjustment recorded for a transfer initiation,
+// oldest first.
+func (d *TransferInitiationDB) Adjustments(ctx context.Context, transferInitiationID string) ([]Adjustment, error) {
+	rows, err := d.store.db.QueryContext(ctx,
+		`SELECT id, transfer_initiation_id, status, error, created_at
+		 FROM adjustments WHERE transfer_initiation_id = $1 ORDER BY created_at ASC`,
+		transferInitiationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []Adjustment
+	for rows.Next() {
+		var a Adjustment
+		if err := rows.Scan(&a.ID, &a.TransferInitiationID, &a.Status, &a.Error, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, a)
+	}
+
+	return adjustments, rows.Err()
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// openTestStore returns a Store backed by EWALLET_TEST_DATABASE_URL,
+// skipping the test when it isn't set. Integration-level store tests that
+// need a real database follow this pattern rather than mocking *sql.DB.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	databaseURL := os.Getenv("EWALLET_TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("EWALLET_TEST_DATABASE_URL not set")
+	}
+
+	st := New(&Config{DatabaseURL: databaseURL})
+	if err := st.Open(); err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	return st
+}
+
+func createTestWallet(t *testing.T, st *Store, label string) *Wallet {
+	t.Helper()
+
+	wallet, err := st.GetWalletDB().Create(context.Background(), uuid.New().String(), "owner-"+label, label, "USD", 1_000_00, nil)
+	if err != nil {
+		t.Fatalf("failed to create wallet %s: %v", label, err)
+	}
+	return wallet
+}
+
+func TestTransferInitiationCreateIsIdempotentOnReference(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	source := createTestWallet(t, st, "source")
+	dest := createTestWallet(t, st, "dest")
+	reference := uuid.New().String()
+
+	first, err := st.GetTransferInitiationDB().Create(ctx, reference, 500, source.ID, dest.ID, "first attempt", time.Now())
+	if err != nil {
+		t.Fatalf("first Create failed: %v", err)
+	}
+
+	second, err := st.GetTransferInitiationDB().Create(ctx, reference, 999, source.ID, dest.ID, "retried attempt", time.Now())
+	if err != nil {
+		t.Fatalf("second Create failed: %v", err)
+	}
+
+	if second.ID != first.ID {
+		t.Fatalf("expected the second Create for the same reference to return the existing initiation %s, got a new one %s", first.ID, second.ID)
+	}
+	if second.Amount != first.Amount {
+		t.Fatalf("expected the repeated Create to leave the original amount %d untouched, got %d", first.Amount, second.Amount)
+	}
+}
+
+func TestTransferInitiationRetryRequiresFailedStatus(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	source := createTestWallet(t, st, "source")
+	dest := createTestWallet(t, st, "dest")
+
+	ti, err := st.GetTransferInitiationDB().Create(ctx, uuid.New().String(), 500, source.ID, dest.ID, "retry guard", time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := st.GetTransferInitiationDB().Retry(ctx, ti.ID); err != ErrTransferInitiationNotRetryable {
+		t.Fatalf("expected Retry on a WAITING initiation to fail with ErrTransferInitiationNotRetryable, got: %v", err)
+	}
+}
+
+func TestTransferInitiationReverseRequiresProcessedStatus(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	source := createTestWallet(t, st, "source")
+	dest := createTestWallet(t, st, "dest")
+
+	ti, err := st.GetTransferInitiationDB().Create(ctx, uuid.New().String(), 500, source.ID, dest.ID, "reverse guard", time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := st.GetTransferInitiationDB().Reverse(ctx, ti.ID); err != ErrTransferInitiationNotReversible {
+		t.Fatalf("expected Reverse on a WAITING initiation to fail with ErrTransferInitiationNotReversible, got: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package store
+
+import "context"
+
+// Wallet represents a single wallet row. Balance is stored in minor units
+// (e.g. cents for USD) of Currency to avoid float rounding drift.
+type Wallet struct {
+	ID        string
+	OwnerID   string
+	Label     string
+	Currency  string
+	Balance   int64
+	PublicKey []byte
+}
+
+// WalletDB manages wallet rows.
+type WalletDB struct {
+	store *Store
+}
+
+// Create inserts a new wallet with the given id, owner, label, currency,
+// starting balance (in minor units), and Ed25519 public key.
+func (w *WalletDB) Create(ctx context.Context, id, ownerID, label, currency string, balance int64, publicKey []byte) (*Wallet, error) {
+	if _, err := w.store.db.ExecContext(ctx,
+		"INSERT INTO wallets (id, owner_id, label, currency, balance, public_key) VALUES ($1, $2, $3, $4, $5, $6)",
+		id, ownerID, label, currency, balance, publicKey,
+	); err != nil {
+		return nil, err
+	}
+
+	return &Wallet{ID: id, OwnerID: ownerID, Label: label, Currency: currency, Balance: balance, PublicKey: publicKey}, nil
+}
+
+// CheckStatus returns the current status of the wallet identified by id.
+func (w *WalletDB) CheckStatus(ctx context.Context, id string) (*Wallet, error) {
+	wallet := &Wallet{}
+	err := w.store.db.QueryRowContext(ctx,
+		"SELECT id, owner_id, label, currency, balance, public_key FROM wallets WHERE id = $1", id,
+	).Scan(&wallet.ID, &wallet.OwnerID, &wallet.Label, &wallet.Currency, &wallet.Balance, &wallet.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// ListByOwner returns every wallet belonging to ownerID.
+func (w *WalletDB) ListByOwner(ctx context.Context, ownerID string) ([]Wallet, error) {
+	rows, err := w.store.db.QueryContext(ctx,
+		"SELECT id, owner_id, label, currency, balance, public_key FROM wallets WHERE owner_id = $1", ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wallets []Wallet
+	for rows.Next() {
+		var wallet Wallet
+		if err := rows.Scan(&wallet.ID, &wallet.OwnerID, &wallet.Label, &wallet.Currency, &wallet.Balance, &wallet.PublicKey); err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, rows.Err()
+}
+
+// Rename updates a wallet's label. It returns ErrWalletNotFound if id does
+// not name an existing wallet.
+func (w *WalletDB) Rename(ctx context.Context, id, label string) error {
+	result, err := w.store.db.ExecContext(ctx, "UPDATE wallets SET label = $1 WHERE id = $2", label, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWalletNotFound
+	}
+
+	return nil
+}
+
+// PublicKey returns the Ed25519 public key registered for the wallet
+// identified by id, for use by the httpsig verifier.
+func (w *WalletDB) PublicKey(ctx context.Context, id string) ([]byte, error) {
+	var key []byte
+	err := w.store.db.QueryRowContext(ctx,
+		"SELECT public_key FROM wallets WHERE id = $1", id,
+	).Scan(&key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"time"
+)
+
+// WalletAddress is a short opaque token that routes an incoming transfer to
+// a wallet, without revealing the wallet's id.
+type WalletAddress struct {
+	Token     string
+	WalletID  string
+	CreatedAt time.Time
+}
+
+// WalletAddressDB manages wallet receive-addresses.
+type WalletAddressDB struct {
+	store *Store
+}
+
+// Create generates and persists a new receive-address for walletID.
+func (a *WalletAddressDB) Create(ctx context.Context, walletID string) (*WalletAddress, error) {
+	token, err := generateAddressToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.store.db.ExecContext(ctx,
+		"INSERT INTO wallet_addresses (token, wallet_id) VALUES ($1, $2)",
+		token, walletID,
+	); err != nil {
+		return nil, err
+	}
+
+	return &WalletAddress{Token: token, WalletID: walletID}, nil
+}
+
+// Resolve returns the wallet id that a receive-address currently routes to.
+// It returns ErrWalletNotFound if the address is unknown.
+func (a *WalletAddressDB) Resolve(ctx context.Context, token string) (string, error) {
+	var walletID string
+	err := a.store.db.QueryRowContext(ctx,
+		"SELECT wallet_id FROM wallet_addresses WHERE token = $1", token,
+	).Scan(&walletID)
+	if err != nil {
+		return "", ErrWalletNotFound
+	}
+
+	return walletID, nil
+}
+
+// ListByWallet returns every receive-address generated for walletID.
+func (a *WalletAddressDB) ListByWallet(ctx context.Context, walletID string) ([]WalletAddress, error) {
+	rows, err := a.store.db.QueryContext(ctx,
+		"SELECT token, wallet_id, created_at FROM wallet_addresses WHERE wallet_id = $1", walletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []WalletAddress
+	for rows.Next() {
+		var addr WalletAddress
+		if err := rows.Scan(&addr.Token, &addr.WalletID, &addr.CreatedAt); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, addr)
+	}
+
+	return addresses, rows.Err()
+}
+
+// generateAddressToken returns a random, URL-safe opaque token suitable for
+// use as a receive-address.
+func generateAddressToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"ewallet/internal/app/store"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode is a stable, machine-readable classification of an AppError,
+// returned to clients in the JSON error envelope.
+type ErrorCode string
+
+const (
+	ErrCodeValidation           ErrorCode = "VALIDATION"
+	ErrCodeNotFound             ErrorCode = "NOT_FOUND"
+	ErrCodeInsufficientFunds    ErrorCode = "INSUFFICIENT_FUNDS"
+	ErrCodeTargetWalletNotFound ErrorCode = "TARGET_WALLET_NOT_FOUND"
+	ErrCodeIdempotencyConflict  ErrorCode = "IDEMPOTENCY_CONFLICT"
+	ErrCodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	ErrCodeInternal             ErrorCode = "INTERNAL"
+)
+
+// AppError is the error type every handler returns. A top-level middleware
+// serializes it into the JSON error envelope instead of the handler writing
+// to the response directly.
+type AppError struct {
+	Status  int
+	Code    ErrorCode
+	Message string
+	Details map[string]interface{}
+	Cause   error
+}
+
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// NewAppError constructs an AppError wrapping cause, which may be nil.
+func NewAppError(status int, code ErrorCode, message string, cause error) *AppError {
+	return &AppError{Status: status, Code: code, Message: message, Cause: cause}
+}
+
+// classifyStoreError maps a store sentinel error to the AppError clients
+// should see. Unrecognized errors become a generic 500 INTERNAL.
+func classifyStoreError(err error) *AppError {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, store.ErrWalletNotFound):
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "wallet not found", err)
+	case errors.Is(err, store.ErrTargetWalletNotFound):
+		return NewAppError(http.StatusNotFound, ErrCodeTargetWalletNotFound, "target wallet not found", err)
+	case errors.Is(err, store.ErrInsufficientFunds):
+		return NewAppError(http.StatusBadRequest, ErrCodeInsufficientFunds, "insufficient funds", err)
+	case errors.Is(err, store.ErrTransferInitiationNotRetryable), errors.Is(err, store.ErrTransferInitiationNotReversible):
+		return NewAppError(http.StatusConflict, ErrCodeValidation, err.Error(), err)
+	default:
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "internal server error", err)
+	}
+}
+
+// errorEnvelope is the JSON shape every error response takes.
+type errorEnvelope struct {
+	ErrorCode    ErrorCode              `json:"error_code"`
+	ErrorMessage string                 `json:"error_message"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+}
+
+// writeError serializes err as the JSON error envelope, classifying plain
+// errors as internal errors.
+func writeError(w http.ResponseWriter, err error) {
+	appErr, ok := err.(*AppError)
+	if !ok {
+		appErr = NewAppError(http.StatusInternalServerError, ErrCodeInternal, "internal server error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(appErr.Status)
+	json.NewEncoder(w).Encode(errorEnvelope{
+		ErrorCode:    appErr.Code,
+		ErrorMessage: appErr.Message,
+		Details:      appErr.Details,
+	})
+}
+
+// appHandlerFunc is the signature every route handler implements under the
+// structured error envelope: handlers return the error instead of writing
+// it, so it can be serialized consistently in one place.
+type appHandlerFunc func(w http.ResponseWriter, r *http.Request) *AppError
+
+// handle adapts an appHandlerFunc into an http.HandlerFunc, serializing any
+// returned AppError as the JSON error envelope.
+func (s *Server) handle(h appHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			writeError(w, err)
+		}
+	}
+}
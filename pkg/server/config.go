@@ -0,0 +1,26 @@
+package server
+
+import (
+	"time"
+
+	"ewallet/internal/app/store"
+)
+
+// Config holds the parameters needed to start the HTTP server.
+type Config struct {
+	BindAddress string
+	StoreCfg    *store.Config
+
+	// SignatureSkew bounds how far an HTTP signature's "created" timestamp
+	// may drift from the server's clock before a request is rejected. Zero
+	// falls back to httpsig.DefaultSkew.
+	SignatureSkew time.Duration
+}
+
+// NewConfig returns a Config populated with sane defaults.
+func NewConfig() *Config {
+	return &Config{
+		BindAddress: ":8080",
+		StoreCfg:    &store.Config{},
+	}
+}
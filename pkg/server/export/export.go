@@ -0,0 +1,75 @@
+// Package export renders a wallet's transaction history into downloadable
+// file formats for the history export endpoint.
+package export
+
+import (
+	"encoding/csv"
+	"ewallet/internal/app/store"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultCurrencyScale and currencyScale mirror pkg/server's per-currency
+// minor-unit scale. Duplicated here rather than imported to keep this
+// package free of a dependency on pkg/server.
+const defaultCurrencyScale = 2
+
+var currencyScale = map[string]int32{
+	"BTC": 8,
+}
+
+func scaleFor(currency string) int32 {
+	if scale, ok := currencyScale[currency]; ok {
+		return scale
+	}
+	return defaultCurrencyScale
+}
+
+// WriteCSV writes transactions as CSV to w: one header row followed by one
+// row per transaction, amounts in decimal form rather than minor units.
+func WriteCSV(w io.Writer, walletID string, transactions []store.Transaction) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{
+		"id", "created_at", "direction", "counterparty_wallet_id",
+		"amount", "currency", "rate",
+	}); err != nil {
+		return err
+	}
+
+	for _, tx := range transactions {
+		direction, counterparty, amount, currency := rowFor(walletID, tx)
+		if err := cw.Write([]string{
+			tx.ID,
+			tx.CreatedAt.UTC().Format(time.RFC3339),
+			direction,
+			counterparty,
+			formatAmount(amount, currency),
+			currency,
+			tx.Rate.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// rowFor resolves the direction, counterparty, and wallet-relative amount
+// and currency of tx from walletID's point of view.
+func rowFor(walletID string, tx store.Transaction) (direction, counterparty string, amount int64, currency string) {
+	if tx.FromWalletID == walletID {
+		return "out", tx.ToWalletID, tx.SourceAmount, tx.SourceCurrency
+	}
+	return "in", tx.FromWalletID, tx.CreditedAmount, tx.CreditedCurrency
+}
+
+// formatAmount renders minor units of currency as a fixed decimal string,
+// matching the server's money formatting convention.
+func formatAmount(minorUnits int64, currency string) string {
+	scale := scaleFor(currency)
+	return decimal.New(minorUnits, -scale).StringFixed(scale)
+}
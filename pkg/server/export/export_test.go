@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"ewallet/internal/app/store"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func testTransactions() []store.Transaction {
+	created := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	return []store.Transaction{
+		{
+			ID:               "tx-out",
+			FromWalletID:     "wallet-1",
+			ToWalletID:       "wallet-2",
+			SourceAmount:     5000,
+			SourceCurrency:   "USD",
+			CreditedAmount:   5000,
+			CreditedCurrency: "USD",
+			Rate:             decimal.NewFromInt(1),
+			CreatedAt:        created,
+		},
+		{
+			ID:               "tx-in",
+			FromWalletID:     "wallet-2",
+			ToWalletID:       "wallet-1",
+			SourceAmount:     1000,
+			SourceCurrency:   "USD",
+			CreditedAmount:   1000,
+			CreditedCurrency: "USD",
+			Rate:             decimal.NewFromInt(1),
+			CreatedAt:        created.Add(time.Hour),
+		},
+	}
+}
+
+func TestWriteCSVIncludesOneRowPerTransactionWithWalletRelativeDirection(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, "wallet-1", testTransactions()); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header row plus 2 transaction rows, got %d lines: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[1], "out") || !strings.Contains(lines[1], "50.00") {
+		t.Errorf("expected the outbound row to report direction out and amount 50.00, got: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "in") || !strings.Contains(lines[2], "10.00") {
+		t.Errorf("expected the inbound row to report direction in and amount 10.00, got: %q", lines[2])
+	}
+}
+
+func TestWriteOFXSignsAmountsByDirection(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteOFX(&buf, "wallet-1", "USD", testTransactions(), time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("WriteOFX failed: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "<TRNAMT>-50.00</TRNAMT>") {
+		t.Errorf("expected the outbound transaction to be negative, got: %q", out)
+	}
+	if !strings.Contains(out, "<TRNAMT>10.00</TRNAMT>") {
+		t.Errorf("expected the inbound transaction to be positive, got: %q", out)
+	}
+	if !strings.Contains(out, "<ACCTID>wallet-1</ACCTID>") {
+		t.Errorf("expected the statement to identify the requested wallet, got: %q", out)
+	}
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"ewallet/internal/app/store"
+	"fmt"
+	"io"
+	"time"
+)
+
+const ofxDateLayout = "20060102150405"
+
+// WriteOFX writes transactions as an OFX 1.0.3 (SGML) bank statement
+// response for walletID, the format most desktop finance software expects
+// for a single-account import.
+func WriteOFX(w io.Writer, walletID, currency string, transactions []store.Transaction, generatedAt time.Time) error {
+	header := "OFXHEADER:100\r\n" +
+		"DATA:OFXSGML\r\n" +
+		"VERSION:103\r\n" +
+		"SECURITY:NONE\r\n" +
+		"ENCODING:UTF-8\r\n" +
+		"CHARSET:NONE\r\n" +
+		"COMPRESSION:NONE\r\n" +
+		"OLDFILEUID:NONE\r\n" +
+		"NEWFILEUID:NONE\r\n\r\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS>\n<CODE>0</CODE>\n<SEVERITY>INFO</SEVERITY>\n</STATUS>\n<DTSERVER>%s</DTSERVER>\n<LANGUAGE>ENG</LANGUAGE>\n</SONRS>\n</SIGNONMSGSRSV1>\n", generatedAt.UTC().Format(ofxDateLayout))
+	fmt.Fprintf(w, "<BANKMSGSRSV1>\n<STMTTRNRS>\n<STMTRS>\n<CURDEF>%s</CURDEF>\n", currency)
+	fmt.Fprintf(w, "<BANKACCTFROM>\n<ACCTID>%s</ACCTID>\n</BANKACCTFROM>\n", walletID)
+	fmt.Fprintf(w, "<BANKTRANLIST>\n")
+
+	for _, tx := range transactions {
+		direction, counterparty, amount, txCurrency := rowFor(walletID, tx)
+		signed := formatAmount(amount, txCurrency)
+		if direction == "out" {
+			signed = "-" + signed
+		}
+
+		fmt.Fprintf(w, "<STMTTRN>\n")
+		fmt.Fprintf(w, "<TRNTYPE>%s</TRNTYPE>\n", ofxTrnType(direction))
+		fmt.Fprintf(w, "<DTPOSTED>%s</DTPOSTED>\n", tx.CreatedAt.UTC().Format(ofxDateLayout))
+		fmt.Fprintf(w, "<TRNAMT>%s</TRNAMT>\n", signed)
+		fmt.Fprintf(w, "<FITID>%s</FITID>\n", tx.ID)
+		fmt.Fprintf(w, "<NAME>%s</NAME>\n", counterparty)
+		fmt.Fprintf(w, "</STMTTRN>\n")
+	}
+
+	fmt.Fprintf(w, "</BANKTRANLIST>\n</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+	return nil
+}
+
+// ofxTrnType maps a transaction's wallet-relative direction to the nearest
+// OFX transaction type code.
+func ofxTrnType(direction string) string {
+	if direction == "out" {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
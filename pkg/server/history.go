@@ -0,0 +1,186 @@
+package server
+
+import (
+	"encoding/json"
+	"ewallet/internal/app/store"
+	"ewallet/pkg/server/export"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const defaultHistoryLimit = 50
+
+// historyResponse is the shape returned by the cursor-paginated history
+// endpoint.
+type historyResponse struct {
+	Data       []store.Transaction `json:"data"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	HasMore    bool                `json:"has_more"`
+}
+
+// getTransactionHistoryHandler returns a cursor-paginated, filterable page
+// of a wallet's transaction history.
+func (s *Server) getTransactionHistoryHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	walletID := mux.Vars(r)["walletId"]
+
+	wallet, err := s.store.GetWalletDB().CheckStatus(r.Context(), walletID)
+	if err != nil {
+		return classifyStoreError(err)
+	}
+
+	filter, appErr := parseHistoryFilter(r, wallet.Currency)
+	if appErr != nil {
+		return appErr
+	}
+
+	transactions, err := s.store.GetTransactionDB().ListHistory(r.Context(), walletID, filter)
+	if err != nil {
+		return classifyStoreError(err)
+	}
+
+	hasMore := len(transactions) > filter.Limit
+	if hasMore {
+		transactions = transactions[:filter.Limit]
+	}
+
+	response := historyResponse{Data: transactions, HasMore: hasMore}
+	if hasMore {
+		last := transactions[len(transactions)-1]
+		response.NextCursor = store.EncodeHistoryCursor(store.HistoryCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+	return nil
+}
+
+// exportTransactionHistoryHandler streams a wallet's full filtered
+// transaction history as a CSV or OFX file, chosen via ?format=.
+func (s *Server) exportTransactionHistoryHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	walletID := mux.Vars(r)["walletId"]
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ofx" {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "format must be csv or ofx", nil)
+	}
+
+	wallet, err := s.store.GetWalletDB().CheckStatus(r.Context(), walletID)
+	if err != nil {
+		return classifyStoreError(err)
+	}
+
+	filter, appErr := parseHistoryFilter(r, wallet.Currency)
+	if appErr != nil {
+		return appErr
+	}
+	filter.Limit = 0 // export is unpaginated; ListHistory's default cap still applies per page
+
+	var transactions []store.Transaction
+	for {
+		page, err := s.store.GetTransactionDB().ListHistory(r.Context(), walletID, filter)
+		if err != nil {
+			return classifyStoreError(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		last := page[len(page)-1]
+		transactions = append(transactions, page...)
+		if len(page) <= defaultHistoryLimit {
+			break
+		}
+
+		cursor := store.HistoryCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		filter.Cursor = &cursor
+	}
+
+	switch format {
+	case "ofx":
+		w.Header().Set("Content-Type", "application/x-ofx")
+		w.Header().Set("Content-Disposition", "attachment; filename=history.ofx")
+		w.WriteHeader(http.StatusOK)
+		export.WriteOFX(w, walletID, wallet.Currency, transactions, time.Now())
+	default:
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=history.csv")
+		w.WriteHeader(http.StatusOK)
+		export.WriteCSV(w, walletID, transactions)
+	}
+
+	return nil
+}
+
+// parseHistoryFilter builds a store.HistoryFilter from the query parameters
+// shared by the paginated and export history endpoints. currency scales
+// min_amount/max_amount the same way the wallet's own balance is scaled.
+func parseHistoryFilter(r *http.Request, currency string) (store.HistoryFilter, *AppError) {
+	q := r.URL.Query()
+	filter := store.HistoryFilter{Limit: defaultHistoryLimit, Direction: store.HistoryDirectionAll}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := store.DecodeHistoryCursor(v)
+		if err != nil {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "invalid cursor", err)
+		}
+		filter.Cursor = &cursor
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "limit must be a positive integer", err)
+		}
+		filter.Limit = limit
+	}
+
+	if v := q.Get("direction"); v != "" {
+		switch store.HistoryDirection(v) {
+		case store.HistoryDirectionIn, store.HistoryDirectionOut, store.HistoryDirectionAll:
+			filter.Direction = store.HistoryDirection(v)
+		default:
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "direction must be in, out, or all", nil)
+		}
+	}
+
+	if v := q.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "since must be an RFC3339 timestamp", err)
+		}
+		filter.Since = &since
+	}
+
+	if v := q.Get("until"); v != "" {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "until must be an RFC3339 timestamp", err)
+		}
+		filter.Until = &until
+	}
+
+	if v := q.Get("min_amount"); v != "" {
+		min, err := parseMinorUnits(v, currency)
+		if err != nil {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "min_amount must be a decimal amount", err)
+		}
+		filter.MinAmount = &min
+	}
+
+	if v := q.Get("max_amount"); v != "" {
+		max, err := parseMinorUnits(v, currency)
+		if err != nil {
+			return filter, NewAppError(http.StatusBadRequest, ErrCodeValidation, "max_amount must be a decimal amount", err)
+		}
+		filter.MaxAmount = &max
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,219 @@
+// Package httpsig implements a minimal subset of RFC 9421 HTTP Message
+// Signatures, scoped to what this server needs: Ed25519-signed requests
+// proving ownership of a wallet's private key.
+package httpsig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSkew is used by NewVerifier when no skew window is supplied.
+const DefaultSkew = 5 * time.Minute
+
+// coveredComponents are the message components included in every signature
+// base produced and expected by this package, in order.
+var coveredComponents = []string{"@method", "@path", "content-digest"}
+
+var signatureInputRe = regexp.MustCompile(`^([a-zA-Z0-9_-]+)=\(([^)]*)\)(.*)$`)
+var createdParamRe = regexp.MustCompile(`created=(\d+)`)
+var contentDigestRe = regexp.MustCompile(`sha-256=:([A-Za-z0-9+/=]+):`)
+
+// KeyResolver looks up the Ed25519 public key associated with a wallet.
+type KeyResolver func(walletID string) (ed25519.PublicKey, error)
+
+// Verifier verifies Ed25519 HTTP Message Signatures on incoming requests.
+type Verifier struct {
+	resolveKey KeyResolver
+	skew       time.Duration
+}
+
+// NewVerifier returns a Verifier that resolves wallet public keys via
+// resolveKey. skew bounds how far the "created" parameter may drift from
+// the current time; if zero, DefaultSkew is used.
+func NewVerifier(resolveKey KeyResolver, skew time.Duration) *Verifier {
+	if skew <= 0 {
+		skew = DefaultSkew
+	}
+	return &Verifier{resolveKey: resolveKey, skew: skew}
+}
+
+// Verify checks the Signature-Input and Signature headers on r against the
+// public key registered for walletID. It returns an error if the headers
+// are missing or malformed, the covered components don't match what this
+// server expects, the created timestamp falls outside the skew window, or
+// the signature itself does not verify.
+func (v *Verifier) Verify(r *http.Request, walletID string) error {
+	label, components, created, err := parseSignatureInput(r.Header.Get("Signature-Input"))
+	if err != nil {
+		return err
+	}
+
+	if !sameComponents(components, coveredComponents) {
+		return errors.New("httpsig: unexpected covered components")
+	}
+
+	if err := verifyContentDigest(r); err != nil {
+		return err
+	}
+
+	if skewed := time.Since(time.Unix(created, 0)); skewed > v.skew || skewed < -v.skew {
+		return errors.New("httpsig: created timestamp outside allowed skew")
+	}
+
+	sig, err := parseSignature(r.Header.Get("Signature"), label)
+	if err != nil {
+		return err
+	}
+
+	pub, err := v.resolveKey(walletID)
+	if err != nil {
+		return err
+	}
+
+	base := signatureBase(r, components, created)
+	if !ed25519.Verify(pub, []byte(base), sig) {
+		return errors.New("httpsig: signature verification failed")
+	}
+
+	return nil
+}
+
+// signatureBase reconstructs the signature base string per RFC 9421 section
+// 2.5, for the fixed set of components this package supports.
+func signatureBase(r *http.Request, components []string, created int64) string {
+	var b strings.Builder
+	for _, c := range components {
+		fmt.Fprintf(&b, "%q: %s\n", c, componentValue(r, c))
+	}
+
+	fmt.Fprintf(&b, "%q: (", "@signature-params")
+	for i, c := range components {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%q", c)
+	}
+	fmt.Fprintf(&b, ");created=%d", created)
+
+	return b.String()
+}
+
+// verifyContentDigest checks that the Content-Digest header on r actually
+// matches the SHA-256 of r.Body, so the signature over the content-digest
+// component binds the signature to the real request body rather than to an
+// unchecked header string. It buffers and restores r.Body so handlers
+// downstream of the middleware can still read it.
+func verifyContentDigest(r *http.Request) error {
+	header := r.Header.Get("content-digest")
+	m := contentDigestRe.FindStringSubmatch(header)
+	if m == nil {
+		return errors.New("httpsig: missing or malformed content-digest header")
+	}
+
+	claimed, err := base64.StdEncoding.DecodeString(m[1])
+	if err != nil {
+		return fmt.Errorf("httpsig: invalid content-digest value: %w", err)
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return fmt.Errorf("httpsig: failed to read request body: %w", err)
+		}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	actual := sha256.Sum256(body)
+	if !bytes.Equal(actual[:], claimed) {
+		return errors.New("httpsig: content-digest does not match request body")
+	}
+
+	return nil
+}
+
+func componentValue(r *http.Request, component string) string {
+	switch component {
+	case "@method":
+		return strings.ToLower(r.Method)
+	case "@path":
+		return r.URL.Path
+	default:
+		return r.Header.Get(component)
+	}
+}
+
+// parseSignatureInput parses a header of the form:
+//
+//	sig1=("@method" "@path" "content-digest");created=1618884475
+func parseSignatureInput(header string) (label string, components []string, created int64, err error) {
+	if header == "" {
+		return "", nil, 0, errors.New("httpsig: missing Signature-Input header")
+	}
+
+	m := signatureInputRe.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return "", nil, 0, errors.New("httpsig: malformed Signature-Input header")
+	}
+
+	label = m[1]
+	for _, c := range strings.Fields(m[2]) {
+		components = append(components, strings.Trim(c, `"`))
+	}
+
+	cm := createdParamRe.FindStringSubmatch(m[3])
+	if cm == nil {
+		return "", nil, 0, errors.New("httpsig: missing created parameter")
+	}
+	created, err = strconv.ParseInt(cm[1], 10, 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("httpsig: invalid created parameter: %w", err)
+	}
+
+	return label, components, created, nil
+}
+
+// parseSignature parses a header of the form: sig1=:base64signature:
+// and returns the raw bytes for the entry matching label.
+func parseSignature(header, label string) ([]byte, error) {
+	if header == "" {
+		return nil, errors.New("httpsig: missing Signature header")
+	}
+
+	for _, entry := range strings.Split(header, ",") {
+		prefix := label + "=:"
+		entry = strings.TrimSpace(entry)
+		if !strings.HasPrefix(entry, prefix) || !strings.HasSuffix(entry, ":") {
+			continue
+		}
+
+		encoded := strings.TrimSuffix(strings.TrimPrefix(entry, prefix), ":")
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+
+	return nil, fmt.Errorf("httpsig: no signature found for label %q", label)
+}
+
+func sameComponents(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// signRequest signs r and body with priv, setting the Content-Digest,
+// Signature-Input, and Signature headers the way a well-behaved client
+// would. created defaults to time.Now() when zero.
+func signRequest(t *testing.T, r *http.Request, priv ed25519.PrivateKey, body []byte, created int64) {
+	t.Helper()
+
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+
+	digest := sha256.Sum256(body)
+	r.Header.Set("content-digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest[:])+":")
+
+	r.Header.Set("Signature-Input", `sig1=("@method" "@path" "content-digest");created=`+strconv.FormatInt(created, 10))
+
+	base := signatureBase(r, coveredComponents, created)
+	sig := ed25519.Sign(priv, []byte(base))
+	r.Header.Set("Signature", "sig1=:"+base64.StdEncoding.EncodeToString(sig)+":")
+}
+
+func newSignedRequest(t *testing.T, priv ed25519.PrivateKey, created int64) *http.Request {
+	t.Helper()
+
+	body := []byte(`{"amount":"10.00"}`)
+	r, err := http.NewRequest(http.MethodPost, "/api/v1/wallet/wallet-1/send", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	signRequest(t, r, priv, body, created)
+	return r
+}
+
+func TestVerifyAcceptsAValidSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	v := NewVerifier(func(walletID string) (ed25519.PublicKey, error) { return pub, nil }, 0)
+	r := newSignedRequest(t, priv, 0)
+
+	if err := v.Verify(r, "wallet-1"); err != nil {
+		t.Fatalf("expected signature to verify, got: %v", err)
+	}
+}
+
+func TestVerifyRejectsWrongKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	v := NewVerifier(func(walletID string) (ed25519.PublicKey, error) { return otherPub, nil }, 0)
+	r := newSignedRequest(t, priv, 0)
+
+	if err := v.Verify(r, "wallet-1"); err == nil {
+		t.Fatal("expected verification to fail against the wrong public key")
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	v := NewVerifier(func(walletID string) (ed25519.PublicKey, error) { return pub, nil }, 0)
+	r := newSignedRequest(t, priv, 0)
+	r.Body = io.NopCloser(bytes.NewReader([]byte(`{"amount":"99999.00"}`)))
+
+	if err := v.Verify(r, "wallet-1"); err == nil {
+		t.Fatal("expected verification to fail when the body no longer matches the content-digest")
+	}
+}
+
+func TestVerifyRejectsMissingSignatureInput(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	v := NewVerifier(func(walletID string) (ed25519.PublicKey, error) { return pub, nil }, 0)
+	r := newSignedRequest(t, priv, 0)
+	r.Header.Del("Signature-Input")
+
+	if err := v.Verify(r, "wallet-1"); err == nil {
+		t.Fatal("expected verification to fail without a Signature-Input header")
+	}
+}
+
+func TestVerifyRejectsStaleTimestamp(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	v := NewVerifier(func(walletID string) (ed25519.PublicKey, error) { return pub, nil }, time.Minute)
+	r := newSignedRequest(t, priv, time.Now().Add(-time.Hour).Unix())
+
+	if err := v.Verify(r, "wallet-1"); err == nil {
+		t.Fatal("expected verification to fail for a created timestamp outside the allowed skew")
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultCurrencyScale is the number of decimal places assumed for a
+// currency's minor unit when it isn't listed in currencyScale (e.g. cents
+// for USD). This matches the ISO 4217 default used by the great majority of
+// currencies.
+const defaultCurrencyScale = 2
+
+// currencyScale overrides defaultCurrencyScale for currencies whose minor
+// unit doesn't use 2 decimal places.
+var currencyScale = map[string]int32{
+	"BTC": 8,
+}
+
+// scaleFor returns the number of decimal places currency's minor unit uses.
+func scaleFor(currency string) int32 {
+	if scale, ok := currencyScale[currency]; ok {
+		return scale
+	}
+	return defaultCurrencyScale
+}
+
+// currencyCodeRe matches the currency codes this server accepts: 3-5
+// uppercase letters, covering ISO 4217 (3 letters, e.g. USD) and the
+// ticker-style codes common to cryptocurrencies (e.g. BTC). Validating
+// against this keeps an unchecked currency field from reaching the FX
+// provider's outgoing request or the SQL layer as freeform text.
+var currencyCodeRe = regexp.MustCompile(`^[A-Z]{3,5}$`)
+
+// errInvalidCurrency is returned by validateCurrency for a currency that
+// doesn't match currencyCodeRe.
+var errInvalidCurrency = errors.New("currency must be 3-5 uppercase letters")
+
+// validateCurrency rejects anything that isn't a plausible currency code.
+func validateCurrency(currency string) error {
+	if !currencyCodeRe.MatchString(currency) {
+		return errInvalidCurrency
+	}
+	return nil
+}
+
+// errNonPositiveAmount is returned by parseTransferAmount for a zero or
+// negative amount.
+var errNonPositiveAmount = errors.New("amount must be greater than zero")
+
+// errNegativeAmount is returned by parseNonNegativeAmount for a negative
+// amount.
+var errNegativeAmount = errors.New("amount must not be negative")
+
+// parseMinorUnits converts a decimal string like "100.00" into an integer
+// count of minor units of currency.
+func parseMinorUnits(amount, currency string) (int64, error) {
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Shift(scaleFor(currency)).Round(0).IntPart(), nil
+}
+
+// parseTransferAmount parses amount like parseMinorUnits, additionally
+// rejecting zero or negative values. Callers that move money between
+// wallets must use this instead of parseMinorUnits: a negative amount would
+// otherwise flip TransferMoney's debit/credit into a credit/debit, letting a
+// caller drain funds from a wallet it doesn't own.
+func parseTransferAmount(amount, currency string) (int64, error) {
+	minorUnits, err := parseMinorUnits(amount, currency)
+	if err != nil {
+		return 0, err
+	}
+	if minorUnits <= 0 {
+		return 0, errNonPositiveAmount
+	}
+
+	return minorUnits, nil
+}
+
+// parseNonNegativeAmount parses amount like parseMinorUnits, additionally
+// rejecting negative values. Callers that seed a wallet's starting balance
+// must use this instead of parseMinorUnits: a negative initial balance would
+// otherwise let a wallet start out already overdrawn.
+func parseNonNegativeAmount(amount, currency string) (int64, error) {
+	minorUnits, err := parseMinorUnits(amount, currency)
+	if err != nil {
+		return 0, err
+	}
+	if minorUnits < 0 {
+		return 0, errNegativeAmount
+	}
+
+	return minorUnits, nil
+}
+
+// formatMinorUnits converts an integer count of minor units of currency back
+// into a decimal string like "100.00".
+func formatMinorUnits(amount int64, currency string) string {
+	scale := scaleFor(currency)
+	return decimal.New(amount, -scale).StringFixed(scale)
+}
@@ -1,21 +1,29 @@
 package server
 
 import (
-	"database/sql"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"ewallet/internal/app/store"
-	"fmt"
+	"ewallet/pkg/server/httpsig"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
-	"net/http"
 )
 
 // Server struct holds the state of the server
 type Server struct {
-	config *Config
-	router *mux.Router
-	store  *store.Store
+	config   *Config
+	router   *mux.Router
+	store    *store.Store
+	verifier *httpsig.Verifier
 }
 
 func New(config *Config) *Server {
@@ -24,22 +32,101 @@ func New(config *Config) *Server {
 	}
 }
 
+const (
+	readTimeout = 10 * time.Second
+	// writeTimeout is generous enough to cover the history export endpoint,
+	// which streams a wallet's full filtered history rather than a single
+	// page.
+	writeTimeout    = 2 * time.Minute
+	shutdownTimeout = 10 * time.Second
+)
+
+// Start brings the server up and blocks until it is shut down by a
+// SIGINT/SIGTERM, or fails to serve. On shutdown, it stops accepting new
+// connections, gives in-flight requests up to shutdownTimeout to finish,
+// and then cancels ctx, which stops the transfer initiation worker. Start
+// returns without waiting for the worker to exit.
 func (s *Server) Start() error {
-	s.configureRouter()
 	if err := s.configureStore(); err != nil {
 		return err
 	}
 
-	return http.ListenAndServe(s.config.BindAddress, s.router)
+	s.configureVerifier()
+	s.configureRouter()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.runTransferInitiationWorker(ctx)
+
+	httpServer := &http.Server{
+		Addr:         s.config.BindAddress,
+		Handler:      s.router,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	select {
+	case err := <-serveErr:
+		cancel()
+		return err
+	case <-sigCh:
+		// Shutdown first, so in-flight requests (whose r.Context() derives
+		// from ctx via BaseContext) get the full shutdownTimeout to finish.
+		// Only once they've drained, or timed out, do we cancel ctx and stop
+		// the background worker.
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer shutdownCancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		cancel()
+		return err
+	}
 }
 
 // configureRouter returns the HTTP handler for the server
 func (s *Server) configureRouter() {
 	r := mux.NewRouter()
-	r.HandleFunc("/api/v1/wallet", s.createWalletHandler).Methods("POST")
-	r.HandleFunc("/api/v1/wallet/{walletId}/send", s.sendMoneyHandler).Methods("POST")
-	r.HandleFunc("/api/v1/wallet/{walletId}/history", s.getTransactionHistoryHandler).Methods("GET")
-	r.HandleFunc("/api/v1/wallet/{walletId}", s.getWalletStatusHandler).Methods("GET")
+	r.HandleFunc("/api/v1/wallet", s.handle(s.createWalletHandler)).Methods("POST")
+	r.HandleFunc("/api/v1/wallets", s.handle(s.listWalletsHandler)).Methods("GET")
+
+	// Wallet-scoped routes that move funds, rename the wallet, mint
+	// addresses, or reveal history are gated behind proof of ownership of
+	// the wallet's private key.
+	walletRouter := r.PathPrefix("/api/v1/wallet/{walletId}").Subrouter()
+	walletRouter.Use(s.verifier.RequireWalletOwnership("walletId"))
+	walletRouter.HandleFunc("/send", s.handle(s.sendMoneyHandler)).Methods("POST")
+	walletRouter.HandleFunc("/label", s.handle(s.updateWalletLabelHandler)).Methods("PUT")
+	walletRouter.HandleFunc("/addresses", s.handle(s.createWalletAddressHandler)).Methods("POST")
+	walletRouter.HandleFunc("/history", s.handle(s.getTransactionHistoryHandler)).Methods("GET")
+	walletRouter.HandleFunc("/history/export", s.handle(s.exportTransactionHistoryHandler)).Methods("GET")
+	walletRouter.HandleFunc("/transfer-initiations", s.handle(s.createTransferInitiationHandler)).Methods("POST")
+	walletRouter.HandleFunc("", s.handle(s.getWalletStatusHandler)).Methods("GET")
+
+	// get/retry/reverse act on a transfer initiation named by id, and list on
+	// a ?wallet_id= query parameter, rather than a walletId path variable, so
+	// they verify wallet ownership directly instead of via walletRouter's
+	// middleware.
+	r.HandleFunc("/api/v1/transfer-initiations", s.handle(s.listTransferInitiationsHandler)).Methods("GET")
+	r.HandleFunc("/api/v1/transfer-initiations/{id}", s.handle(s.getTransferInitiationHandler)).Methods("GET")
+	r.HandleFunc("/api/v1/transfer-initiations/{id}/retry", s.handle(s.retryTransferInitiationHandler)).Methods("POST")
+	r.HandleFunc("/api/v1/transfer-initiations/{id}/reverse", s.handle(s.reverseTransferInitiationHandler)).Methods("POST")
+
 	s.router = r
 }
 
@@ -53,123 +140,139 @@ func (s *Server) configureStore() error {
 	return nil
 }
 
+// configureVerifier sets up the httpsig verifier used to gate wallet-scoped
+// routes, resolving each wallet's public key through the store.
+func (s *Server) configureVerifier() {
+	s.verifier = httpsig.NewVerifier(func(walletID string) (ed25519.PublicKey, error) {
+		key, err := s.store.GetWalletDB().PublicKey(context.Background(), walletID)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(key), nil
+	}, s.config.SignatureSkew)
+}
+
+// requireWalletSignature verifies r carries a valid signature proving
+// ownership of walletID. It's used by routes that act on a wallet without
+// a walletId path variable of their own (so they can't use walletRouter's
+// RequireWalletOwnership middleware directly), such as retrying or
+// reversing a transfer initiation named only by its own id.
+func (s *Server) requireWalletSignature(r *http.Request, walletID string) *AppError {
+	if err := s.verifier.Verify(r, walletID); err != nil {
+		return NewAppError(http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized", err)
+	}
+	return nil
+}
+
 // createWalletHandler handles the creation of a new wallet
-func (s *Server) createWalletHandler(w http.ResponseWriter, r *http.Request) {
-	newUuid := uuid.New().String()
-	fmt.Println(newUuid)
-	var db = s.store.GetWalletDB()
-	wallet, err := db.Create(newUuid, 100)
+func (s *Server) createWalletHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	var request struct {
+		OwnerID        string `json:"owner_id"`
+		Label          string `json:"label"`
+		Currency       string `json:"currency"`
+		InitialBalance string `json:"initial_balance"`
+		PublicKey      string `json:"public_key"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "invalid request body", err)
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(request.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "public_key must be a base64-encoded Ed25519 public key", err)
+	}
+
+	if err := validateCurrency(request.Currency); err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, err.Error(), err)
+	}
+
+	initialBalance, err := parseNonNegativeAmount(request.InitialBalance, request.Currency)
 	if err != nil {
-		http.Error(w, "Failed to create wallet", http.StatusInternalServerError)
-		return
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "initial_balance must be a decimal amount", err)
 	}
 
-	response := struct {
-		ID      string  `json:"id"`
-		Balance float64 `json:"balance"`
-	}{
-		ID:      wallet.ID,
-		Balance: wallet.Balance,
+	newUuid := uuid.New().String()
+	var db = s.store.GetWalletDB()
+	wallet, err := db.Create(r.Context(), newUuid, request.OwnerID, request.Label, request.Currency, initialBalance, publicKey)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to create wallet", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(walletResponse(wallet))
+	return nil
 }
 
 // getWalletStatusHandler handles retrieving the current status of a wallet
-func (s *Server) getWalletStatusHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) getWalletStatusHandler(w http.ResponseWriter, r *http.Request) *AppError {
 	vars := mux.Vars(r)
 	walletID := vars["walletId"]
 
-	// Получение состояния кошелька
-	wallet, err := s.store.WalletDB.CheckStatus(walletID)
+	// Look up the wallet's current balance and currency.
+	wallet, err := s.store.WalletDB.CheckStatus(r.Context(), walletID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "wallet not found", http.StatusNotFound)
-			return
-		}
-		http.Error(w, "internal Server Error", http.StatusInternalServerError)
-		return
+		return classifyStoreError(err)
+	}
+
+	pendingOutbound, err := s.store.GetTransferInitiationDB().PendingOutboundTotal(r.Context(), walletID)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to compute available balance", err)
 	}
 
-	// Формирование ответа в формате JSON с ID и балансом созданного кошелька
 	response := struct {
-		ID      string  `json:"id"`
-		Balance float64 `json:"balance"`
+		ID               string `json:"id"`
+		Balance          string `json:"balance"`
+		Currency         string `json:"currency"`
+		AvailableBalance string `json:"available_balance"`
 	}{
-		ID:      wallet.ID,
-		Balance: wallet.Balance,
+		ID:               wallet.ID,
+		Balance:          formatMinorUnits(wallet.Balance, wallet.Currency),
+		Currency:         wallet.Currency,
+		AvailableBalance: formatMinorUnits(wallet.Balance-pendingOutbound, wallet.Currency),
 	}
 
-	// Установка заголовка Content-Type для указания формата ответа
 	w.Header().Set("Content-Type", "application/json")
-
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
+	return nil
 }
 
 // sendMoneyHandler handles money transfer between wallets
-func (s *Server) sendMoneyHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) sendMoneyHandler(w http.ResponseWriter, r *http.Request) *AppError {
 	vars := mux.Vars(r)
 	from := vars["walletId"]
 
 	var request struct {
-		To     string  `json:"to"`
-		Amount float64 `json:"amount"`
+		To     string `json:"to"`
+		Amount string `json:"amount"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		return
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "invalid request body", err)
 	}
 
-	var db = s.store.GetTransactionDB()
-	err := db.TransferMoney(from, request.To, request.Amount)
+	sourceWallet, err := s.store.GetWalletDB().CheckStatus(r.Context(), from)
 	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			// Если кошелек не найден, возвращаем статус ответа 404
-			http.Error(w, "sender wallet not found", http.StatusNotFound)
-		case errors.Is(err, errors.New("there are not enough funds")):
-			// Если недостаточно средств на кошельке, возвращаем статус ответа 400
-			http.Error(w, "not enough funds", http.StatusBadRequest)
-		case errors.Is(err, errors.New("target wallet not found")):
-			// Если целевой кошелек не найден, возвращаем статус ответа 404
-			http.Error(w, "target wallet not found", http.StatusNotFound)
-		default:
-			// В случае других ошибок, возвращаем статус ответа 500
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		}
-		return
+		return classifyStoreError(err)
 	}
 
-	// Если успешно, возвращаем статус ответа 200
-	w.WriteHeader(http.StatusOK)
+	amount, err := parseTransferAmount(request.Amount, sourceWallet.Currency)
+	if err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "amount must be a positive decimal amount", err)
+	}
 
-}
+	to := request.To
+	if walletID, err := s.store.GetWalletAddressDB().Resolve(r.Context(), to); err == nil {
+		to = walletID
+	}
 
-// getTransactionHistoryHandler handles retrieving transaction history for a wallet
-func (s *Server) getTransactionHistoryHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	from := vars["walletId"]
 	var db = s.store.GetTransactionDB()
-
-	transactions, err := db.GetWalletTransactions(from)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "sender wallet not found", http.StatusNotFound)
-			return
-		}
-		return
+	if err := db.TransferMoney(r.Context(), from, to, amount); err != nil {
+		return classifyStoreError(err)
 	}
 
-	// Return the transaction history as JSON
+	// Transfer succeeded; nothing else to report back to the caller.
 	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(transactions)
-	if err != nil {
-		return
-	}
-
+	return nil
 }
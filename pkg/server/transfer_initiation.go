@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"ewallet/internal/app/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const transferInitiationWorkerInterval = 5 * time.Second
+
+// runTransferInitiationWorker polls for WAITING transfer initiations whose
+// ScheduledAt has passed and drives them through the transfer. It is meant
+// to run for the lifetime of the server as a background goroutine, exiting
+// once ctx is cancelled during shutdown.
+func (s *Server) runTransferInitiationWorker(ctx context.Context) {
+	ticker := time.NewTicker(transferInitiationWorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			db := s.store.GetTransferInitiationDB()
+			due, err := db.DueForProcessing(ctx)
+			if err != nil {
+				continue
+			}
+
+			for _, ti := range due {
+				s.processTransferInitiation(ctx, ti)
+			}
+		}
+	}
+}
+
+// processTransferInitiation moves a single transfer initiation from WAITING
+// to PROCESSING and then to PROCESSED or FAILED, recording an adjustment at
+// every step.
+func (s *Server) processTransferInitiation(ctx context.Context, ti store.TransferInitiation) {
+	db := s.store.GetTransferInitiationDB()
+
+	if err := db.UpdateStatus(ctx, ti.ID, store.TransferInitiationProcessing); err != nil {
+		return
+	}
+	_ = db.AddAdjustment(ctx, ti.ID, store.TransferInitiationProcessing, "")
+
+	transferErr := s.store.GetTransactionDB().TransferMoney(ctx, ti.SourceWalletID, ti.DestinationWalletID, ti.Amount)
+	if transferErr != nil {
+		_ = db.UpdateStatus(ctx, ti.ID, store.TransferInitiationFailed)
+		_ = db.AddAdjustment(ctx, ti.ID, store.TransferInitiationFailed, transferErr.Error())
+		return
+	}
+
+	_ = db.UpdateStatus(ctx, ti.ID, store.TransferInitiationProcessed)
+	_ = db.AddAdjustment(ctx, ti.ID, store.TransferInitiationProcessed, "")
+}
+
+// createTransferInitiationHandler creates a new transfer initiation, or
+// returns the existing one for the given Reference (idempotency).
+func (s *Server) createTransferInitiationHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	vars := mux.Vars(r)
+	sourceWalletID := vars["walletId"]
+
+	var request struct {
+		Reference           string    `json:"reference"`
+		Amount              string    `json:"amount"`
+		DestinationWalletID string    `json:"destination_wallet_id"`
+		Description         string    `json:"description"`
+		ScheduledAt         time.Time `json:"scheduled_at"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "invalid request body", err)
+	}
+
+	if request.Reference == "" {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "reference is required", nil)
+	}
+
+	sourceWallet, err := s.store.GetWalletDB().CheckStatus(r.Context(), sourceWalletID)
+	if err != nil {
+		return classifyStoreError(err)
+	}
+
+	amount, err := parseTransferAmount(request.Amount, sourceWallet.Currency)
+	if err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "amount must be a positive decimal amount", err)
+	}
+
+	if request.ScheduledAt.IsZero() {
+		request.ScheduledAt = time.Now()
+	}
+
+	db := s.store.GetTransferInitiationDB()
+	ti, err := db.Create(r.Context(), request.Reference, amount, sourceWalletID, request.DestinationWalletID, request.Description, request.ScheduledAt)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to create transfer initiation", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ti)
+	return nil
+}
+
+// getTransferInitiationHandler returns a single transfer initiation by id.
+// Since it reveals the amount and both wallet ids, it requires proof of
+// ownership of the source or destination wallet.
+func (s *Server) getTransferInitiationHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	id := mux.Vars(r)["id"]
+
+	ti, err := s.store.GetTransferInitiationDB().Get(r.Context(), id)
+	if err != nil {
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "transfer initiation not found", err)
+	}
+
+	if s.requireWalletSignature(r, ti.SourceWalletID) != nil && s.requireWalletSignature(r, ti.DestinationWalletID) != nil {
+		return NewAppError(http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized", nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ti)
+	return nil
+}
+
+// listTransferInitiationsHandler returns a page of transfer initiations
+// sourced from or destined to the wallet proven by ?wallet_id=, newest
+// first, using ?page= and ?limit= query parameters.
+func (s *Server) listTransferInitiationsHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	walletID := r.URL.Query().Get("wallet_id")
+	if walletID == "" {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "wallet_id is required to prove ownership", nil)
+	}
+	if appErr := s.requireWalletSignature(r, walletID); appErr != nil {
+		return appErr
+	}
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	initiations, err := s.store.GetTransferInitiationDB().ListForWallet(r.Context(), walletID, limit, (page-1)*limit)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to list transfer initiations", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(initiations)
+	return nil
+}
+
+// retryTransferInitiationHandler resets a FAILED transfer initiation back to
+// WAITING so the worker retries it. Since the retry resends funds from the
+// initiation's source wallet, it requires the same proof of ownership of
+// that wallet that creating the initiation did.
+func (s *Server) retryTransferInitiationHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	id := mux.Vars(r)["id"]
+
+	db := s.store.GetTransferInitiationDB()
+	existing, err := db.Get(r.Context(), id)
+	if err != nil {
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "transfer initiation not found", err)
+	}
+	if appErr := s.requireWalletSignature(r, existing.SourceWalletID); appErr != nil {
+		return appErr
+	}
+
+	ti, err := db.Retry(r.Context(), id)
+	if err != nil {
+		if err == store.ErrTransferInitiationNotRetryable {
+			return classifyStoreError(err)
+		}
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "transfer initiation not found", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ti)
+	return nil
+}
+
+// reverseTransferInitiationHandler enqueues a mirror transfer from
+// destination back to source for a PROCESSED transfer initiation. The
+// reversal debits the original destination wallet, so it requires proof of
+// ownership of that wallet, not the original source.
+func (s *Server) reverseTransferInitiationHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	id := mux.Vars(r)["id"]
+
+	db := s.store.GetTransferInitiationDB()
+	existing, err := db.Get(r.Context(), id)
+	if err != nil {
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "transfer initiation not found", err)
+	}
+	if appErr := s.requireWalletSignature(r, existing.DestinationWalletID); appErr != nil {
+		return appErr
+	}
+
+	mirror, err := db.Reverse(r.Context(), id)
+	if err != nil {
+		if err == store.ErrTransferInitiationNotReversible {
+			return classifyStoreError(err)
+		}
+		return NewAppError(http.StatusNotFound, ErrCodeNotFound, "transfer initiation not found", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(mirror)
+	return nil
+}
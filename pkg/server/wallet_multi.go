@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"ewallet/internal/app/store"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// walletResponse projects a store.Wallet into the shape returned by the
+// wallet handlers.
+func walletResponse(wallet *store.Wallet) interface{} {
+	return struct {
+		ID       string `json:"id"`
+		OwnerID  string `json:"owner_id"`
+		Label    string `json:"label"`
+		Currency string `json:"currency"`
+		Balance  string `json:"balance"`
+	}{
+		ID:       wallet.ID,
+		OwnerID:  wallet.OwnerID,
+		Label:    wallet.Label,
+		Currency: wallet.Currency,
+		Balance:  formatMinorUnits(wallet.Balance, wallet.Currency),
+	}
+}
+
+// listWalletsHandler returns every wallet belonging to ?owner_id=..., proven
+// by a valid signature for one of that owner's own wallets, named by
+// ?wallet_id=. Without this, anyone who knows or guesses an owner_id could
+// enumerate every wallet (and its balance) belonging to it.
+func (s *Server) listWalletsHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	ownerID := r.URL.Query().Get("owner_id")
+	if ownerID == "" {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "owner_id is required", nil)
+	}
+
+	proofWalletID := r.URL.Query().Get("wallet_id")
+	if proofWalletID == "" {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "wallet_id is required to prove ownership", nil)
+	}
+	if appErr := s.requireWalletSignature(r, proofWalletID); appErr != nil {
+		return appErr
+	}
+
+	proofWallet, err := s.store.GetWalletDB().CheckStatus(r.Context(), proofWalletID)
+	if err != nil {
+		return classifyStoreError(err)
+	}
+	if proofWallet.OwnerID != ownerID {
+		return NewAppError(http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized", nil)
+	}
+
+	wallets, err := s.store.GetWalletDB().ListByOwner(r.Context(), ownerID)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to list wallets", err)
+	}
+
+	response := make([]interface{}, len(wallets))
+	for i := range wallets {
+		response[i] = walletResponse(&wallets[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+	return nil
+}
+
+// updateWalletLabelHandler renames a wallet.
+func (s *Server) updateWalletLabelHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	walletID := mux.Vars(r)["walletId"]
+
+	var request struct {
+		Label string `json:"label"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return NewAppError(http.StatusBadRequest, ErrCodeValidation, "invalid request body", err)
+	}
+
+	if err := s.store.GetWalletDB().Rename(r.Context(), walletID, request.Label); err != nil {
+		return classifyStoreError(err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// createWalletAddressHandler generates a new receive-address for a wallet.
+func (s *Server) createWalletAddressHandler(w http.ResponseWriter, r *http.Request) *AppError {
+	walletID := mux.Vars(r)["walletId"]
+
+	address, err := s.store.GetWalletAddressDB().Create(r.Context(), walletID)
+	if err != nil {
+		return NewAppError(http.StatusInternalServerError, ErrCodeInternal, "failed to create wallet address", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(address)
+	return nil
+}